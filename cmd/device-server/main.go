@@ -12,9 +12,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/dsrose15/device-monitor/internal/auth"
+	"github.com/dsrose15/device-monitor/internal/devices"
 	"github.com/dsrose15/device-monitor/internal/routes"
+	"github.com/dsrose15/device-monitor/internal/stream"
+	"github.com/dsrose15/device-monitor/internal/webhooks"
 )
 
+const minPoolConns = 5
+
 type App struct {
 	Router *gin.Engine
 	DB     *pgxpool.Pool
@@ -28,6 +34,32 @@ func main() {
 	}
 	defer db.Close()
 
+	// Apply any pending schema migrations
+	migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer migrateCancel()
+	if err := devices.Migrate(migrateCtx, db); err != nil {
+		log.Fatal("Failed to apply migrations:", err)
+	}
+	if err := auth.Migrate(migrateCtx, db); err != nil {
+		log.Fatal("Failed to apply migrations:", err)
+	}
+	if err := stream.Migrate(migrateCtx, db); err != nil {
+		log.Fatal("Failed to apply migrations:", err)
+	}
+	if err := webhooks.Migrate(migrateCtx, db); err != nil {
+		log.Fatal("Failed to apply migrations:", err)
+	}
+
+	// Fan telemetry NOTIFY payloads out to subscribed WebSocket clients,
+	// including ones ingested by other app instances behind a load balancer.
+	hub := stream.NewHub()
+	go runTelemetryListener(db, hub)
+
+	// Claim and deliver outbound webhook events, and periodically flag
+	// devices that have gone quiet as offline.
+	go webhooks.RunDispatcher(context.Background(), db)
+	go webhooks.RunOfflineDetector(context.Background(), db)
+
 	// Initialize Gin router
 	router := gin.Default()
 
@@ -38,7 +70,7 @@ func main() {
 	}
 
 	// Setup routes
-	routes.SetupRoutes(app.Router, app.DB)
+	routes.SetupRoutes(app.Router, app.DB, hub, minPoolConns)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -50,6 +82,18 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+port, router))
 }
 
+// runTelemetryListener keeps stream.Listen running for the lifetime of the
+// process, reconnecting with a fixed backoff if the LISTEN connection is
+// lost.
+func runTelemetryListener(pool *pgxpool.Pool, hub *stream.Hub) {
+	for {
+		if err := stream.Listen(context.Background(), pool, hub); err != nil {
+			log.Printf("telemetry listener stopped, reconnecting: %v", err)
+			time.Sleep(5 * time.Second)
+		}
+	}
+}
+
 func initDB() (*pgxpool.Pool, error) {
 	// Database connection string
 	// You can set this via environment variable: DATABASE_URL
@@ -66,7 +110,7 @@ func initDB() (*pgxpool.Pool, error) {
 
 	// Set connection pool settings
 	config.MaxConns = 30
-	config.MinConns = 5
+	config.MinConns = minPoolConns
 	config.MaxConnLifetime = time.Hour
 	config.MaxConnIdleTime = time.Minute * 30
 
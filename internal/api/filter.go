@@ -0,0 +1,80 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// filterOperators maps the filter DSL's operator tokens to the SQL they
+// compile to. Only these operators are ever emitted into a query.
+var filterOperators = map[string]string{
+	"eq":  "=",
+	"neq": "!=",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+// FilterCondition is one predicate parsed from a ?filter= DSL string. It
+// is safe to interpolate Column and Operator into SQL because both are
+// checked against an allow-list before a FilterCondition is created;
+// Value is never interpolated and must be passed as a query argument.
+type FilterCondition struct {
+	Column   string
+	Operator string
+	Value    string
+}
+
+// ParseFilters parses a comma-separated ?filter=column:op:value,... DSL
+// string into FilterConditions, rejecting any column not in
+// allowedColumns and any operator not in filterOperators.
+func ParseFilters(raw string, allowedColumns []string) ([]FilterCondition, error) {
+	var conditions []FilterCondition
+
+	for _, clause := range strings.Split(raw, ",") {
+		if clause == "" {
+			continue
+		}
+
+		parts := strings.SplitN(clause, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid filter clause: %s", clause)
+		}
+
+		column, op, value := parts[0], parts[1], parts[2]
+
+		if !isAllowedColumn(column, allowedColumns) {
+			return nil, fmt.Errorf("invalid filter column: %s", column)
+		}
+
+		sqlOp, ok := filterOperators[op]
+		if !ok {
+			return nil, fmt.Errorf("invalid filter operator: %s", op)
+		}
+
+		conditions = append(conditions, FilterCondition{Column: column, Operator: sqlOp, Value: value})
+	}
+
+	return conditions, nil
+}
+
+// BuildWhereClause renders conditions into a SQL fragment beginning with
+// " AND ", using placeholders numbered from argOffset+1, and returns the
+// fragment along with the ordered argument values to pass alongside it.
+// Values are always passed as parameterized arguments, never
+// concatenated into the fragment itself.
+func BuildWhereClause(conditions []FilterCondition, argOffset int) (string, []any) {
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	clauses := make([]string, len(conditions))
+	args := make([]any, len(conditions))
+	for i, cond := range conditions {
+		clauses[i] = fmt.Sprintf("%s %s $%d", cond.Column, cond.Operator, argOffset+i+1)
+		args[i] = cond.Value
+	}
+
+	return " AND " + strings.Join(clauses, " AND "), args
+}
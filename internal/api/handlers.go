@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,8 +10,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
 )
 
+var userAllowedColumns = []string{"id", "name", "email", "created_at", "updated_at"}
+
 type Handlers struct {
 	DB *pgxpool.Pool
 }
@@ -27,28 +31,6 @@ func NewHandlers(db *pgxpool.Pool) *Handlers {
 	return &Handlers{DB: db}
 }
 
-// Health check endpoint
-func (h *Handlers) HealthCheck(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Check database connection
-	if err := h.DB.Ping(ctx); err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status":  "unhealthy",
-			"message": "Database connection failed",
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "healthy",
-		"message": "Service is running",
-		"time":    time.Now(),
-	})
-}
-
 // Simple ping endpoint
 func (h *Handlers) Ping(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -66,11 +48,32 @@ func (h *Handlers) Index(c *gin.Context) {
 
 // Get all users
 func (h *Handlers) GetUsers(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	query := `SELECT id, name, email, created_at, updated_at FROM users ORDER BY created_at DESC`
-	rows, err := h.DB.Query(ctx, query)
+	params, err := ParsePageParams(c, userAllowedColumns, "created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := `SELECT id, name, email, created_at, updated_at FROM users WHERE 1=1`
+	var args []any
+
+	filterClause, filterArgs := BuildWhereClause(params.Filters, len(args))
+	query += filterClause
+	args = append(args, filterArgs...)
+
+	cursorClause, cursorArgs := CursorClause(params.Cursor, params.SortOrder, len(args))
+	query += cursorClause
+	args = append(args, cursorArgs...)
+
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d", params.SortColumn, params.SortOrder, params.SortOrder, len(args)+1)
+	args = append(args, params.Limit+1)
+
+	rows, err := h.DB.Query(ctx, query, args...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch users",
@@ -99,15 +102,34 @@ func (h *Handlers) GetUsers(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"users": users,
-		"count": len(users),
+	hasMore := len(users) > params.Limit
+	if hasMore {
+		users = users[:params.Limit]
+	}
+
+	var nextCursor *string
+	if hasMore && len(users) > 0 {
+		last := users[len(users)-1]
+		token, err := EncodeCursor(Cursor{LastCreatedAt: last.CreatedAt, LastID: last.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to encode next cursor",
+			})
+			return
+		}
+		nextCursor = &token
+	}
+
+	c.JSON(http.StatusOK, PageResult{
+		Data:       users,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
 	})
 }
 
 // Get user by ID
 func (h *Handlers) GetUser(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	idStr := c.Param("id")
@@ -143,12 +165,13 @@ func (h *Handlers) GetUser(c *gin.Context) {
 
 // Create new user
 func (h *Handlers) CreateUser(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	var newUser struct {
-		Name  string `json:"name" binding:"required"`
-		Email string `json:"email" binding:"required,email"`
+		Name     string `json:"name" binding:"required"`
+		Email    string `json:"email" binding:"required,email"`
+		Password string `json:"password" binding:"required,min=8"`
 	}
 
 	if err := c.ShouldBindJSON(&newUser); err != nil {
@@ -158,12 +181,20 @@ func (h *Handlers) CreateUser(c *gin.Context) {
 		return
 	}
 
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(newUser.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to hash password",
+		})
+		return
+	}
+
 	var user User
-	query := `INSERT INTO users (name, email, created_at, updated_at) 
-			  VALUES ($1, $2, NOW(), NOW()) 
+	query := `INSERT INTO users (name, email, password_hash, created_at, updated_at)
+			  VALUES ($1, $2, $3, NOW(), NOW())
 			  RETURNING id, name, email, created_at, updated_at`
 
-	err := h.DB.QueryRow(ctx, query, newUser.Name, newUser.Email).Scan(
+	err = h.DB.QueryRow(ctx, query, newUser.Name, newUser.Email, string(passwordHash)).Scan(
 		&user.ID, &user.Name, &user.Email, &user.CreatedAt, &user.UpdatedAt,
 	)
 
@@ -182,7 +213,7 @@ func (h *Handlers) CreateUser(c *gin.Context) {
 
 // Update user
 func (h *Handlers) UpdateUser(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	idStr := c.Param("id")
@@ -236,7 +267,7 @@ func (h *Handlers) UpdateUser(c *gin.Context) {
 
 // Delete user
 func (h *Handlers) DeleteUser(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
 	idStr := c.Param("id")
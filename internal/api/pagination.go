@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// SortOrder is either ascending or descending.
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// Cursor identifies a position in a keyset-paginated result set, ordered
+// by created_at then id as a tiebreaker.
+type Cursor struct {
+	LastCreatedAt time.Time `json:"last_created_at"`
+	LastID        int       `json:"last_id"`
+}
+
+// EncodeCursor renders a cursor as the opaque, base64-encoded token
+// returned to and accepted back from clients.
+func EncodeCursor(cursor Cursor) (string, error) {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor parses a cursor token produced by EncodeCursor.
+func DecodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+	raw, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return cursor, fmt.Errorf("invalid cursor payload: %w", err)
+	}
+	return cursor, nil
+}
+
+// PageParams is a parsed and validated set of list-endpoint query
+// parameters: limit, sort column/order, an optional cursor, and filter
+// predicates.
+type PageParams struct {
+	Limit      int
+	SortColumn string
+	SortOrder  SortOrder
+	Cursor     *Cursor
+	Filters    []FilterCondition
+}
+
+// ParsePageParams parses ?limit=, ?sort_column=, ?sort_order=, ?cursor=
+// and ?filter= from the request. sort_column and every filter field are
+// validated against allowedColumns so callers can build SQL from trusted
+// identifiers instead of string concatenation.
+func ParsePageParams(c *gin.Context, allowedColumns []string, defaultSortColumn string) (PageParams, error) {
+	params := PageParams{
+		Limit:      defaultLimit,
+		SortColumn: defaultSortColumn,
+		SortOrder:  SortDesc,
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return params, fmt.Errorf("invalid limit: %s", limitStr)
+		}
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+		params.Limit = limit
+	}
+
+	if sortColumn := c.Query("sort_column"); sortColumn != "" {
+		if !isAllowedColumn(sortColumn, allowedColumns) {
+			return params, fmt.Errorf("invalid sort_column: %s", sortColumn)
+		}
+		params.SortColumn = sortColumn
+	}
+
+	if sortOrder := c.Query("sort_order"); sortOrder != "" {
+		switch strings.ToLower(sortOrder) {
+		case string(SortAsc):
+			params.SortOrder = SortAsc
+		case string(SortDesc):
+			params.SortOrder = SortDesc
+		default:
+			return params, fmt.Errorf("invalid sort_order: %s", sortOrder)
+		}
+	}
+
+	if cursorToken := c.Query("cursor"); cursorToken != "" {
+		cursor, err := DecodeCursor(cursorToken)
+		if err != nil {
+			return params, err
+		}
+		// CursorClause only ever filters on (created_at, id); pairing a
+		// cursor with any other sort_column would order by one column
+		// while paging on another, silently skipping or repeating rows.
+		if params.SortColumn != defaultSortColumn {
+			return params, fmt.Errorf("cursor-based pagination requires the default sort_column (%s)", defaultSortColumn)
+		}
+		params.Cursor = &cursor
+	}
+
+	if filterStr := c.Query("filter"); filterStr != "" {
+		filters, err := ParseFilters(filterStr, allowedColumns)
+		if err != nil {
+			return params, err
+		}
+		params.Filters = filters
+	}
+
+	return params, nil
+}
+
+func isAllowedColumn(column string, allowed []string) bool {
+	for _, candidate := range allowed {
+		if candidate == column {
+			return true
+		}
+	}
+	return false
+}
+
+// CursorClause returns a SQL fragment implementing keyset pagination on
+// (created_at, id), with placeholders numbered from argOffset+1, plus its
+// argument values in order. It returns "" if cursor is nil. Callers must
+// only pass a non-nil cursor when ordering by the default sort_column
+// (ParsePageParams enforces this), since the clause always compares
+// against created_at regardless of the requested sort_column.
+func CursorClause(cursor *Cursor, order SortOrder, argOffset int) (string, []any) {
+	if cursor == nil {
+		return "", nil
+	}
+
+	cmp := "<"
+	if order == SortAsc {
+		cmp = ">"
+	}
+
+	clause := fmt.Sprintf(" AND (created_at %s $%d OR (created_at = $%d AND id %s $%d))",
+		cmp, argOffset+1, argOffset+1, cmp, argOffset+2)
+	return clause, []any{cursor.LastCreatedAt, cursor.LastID}
+}
+
+// PageResult is the standard cursor-paginated list response envelope.
+type PageResult struct {
+	Data       any     `json:"data"`
+	NextCursor *string `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+}
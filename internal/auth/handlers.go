@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Handlers struct {
+	DB *pgxpool.Pool
+}
+
+func NewHandlers(db *pgxpool.Pool) *Handlers {
+	return &Handlers{DB: db}
+}
+
+type tokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+}
+
+func (h *Handlers) issueTokenPair(ctx context.Context, userID int, role string) (tokenPair, error) {
+	accessToken, err := GenerateAccessToken(userID, role)
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	refreshToken, jti, err := GenerateRefreshToken(userID, role)
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	query := `INSERT INTO refresh_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3)`
+	if _, err := h.DB.Exec(ctx, query, jti, userID, time.Now().Add(RefreshTokenTTL)); err != nil {
+		return tokenPair{}, err
+	}
+
+	return tokenPair{AccessToken: accessToken, RefreshToken: refreshToken, TokenType: "Bearer"}, nil
+}
+
+// Login exchanges an email/password for an access and refresh token pair.
+func (h *Handlers) Login(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req struct {
+		Email    string `json:"email" binding:"required,email"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var userID int
+	var passwordHash, role string
+	query := `SELECT id, password_hash, role FROM users WHERE email = $1`
+	err := h.DB.QueryRow(ctx, query, req.Email).Scan(&userID, &passwordHash, &role)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid email or password",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch user",
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid email or password",
+		})
+		return
+	}
+
+	tokens, err := h.issueTokenPair(ctx, userID, role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to issue tokens",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Refresh rotates a valid, unrevoked refresh token for a new token pair.
+func (h *Handlers) Refresh(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	claims, err := ParseToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid or expired refresh token",
+		})
+		return
+	}
+
+	var revoked bool
+	err = h.DB.QueryRow(ctx, `SELECT revoked FROM refresh_tokens WHERE jti = $1`, claims.ID).Scan(&revoked)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Refresh token not recognized",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to look up refresh token",
+		})
+		return
+	}
+	if revoked {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Refresh token has been revoked",
+		})
+		return
+	}
+
+	if _, err := h.DB.Exec(ctx, `UPDATE refresh_tokens SET revoked = TRUE WHERE jti = $1`, claims.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke used refresh token",
+		})
+		return
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid token subject",
+		})
+		return
+	}
+
+	tokens, err := h.issueTokenPair(ctx, userID, claims.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to issue tokens",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout revokes a refresh token so it can no longer be used to mint new
+// access tokens.
+func (h *Handlers) Logout(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	claims, err := ParseToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid or expired refresh token",
+		})
+		return
+	}
+
+	if _, err := h.DB.Exec(ctx, `UPDATE refresh_tokens SET revoked = TRUE WHERE jti = $1`, claims.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to revoke refresh token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logged out successfully",
+	})
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Required ensures the request carries a valid access token and makes the
+// authenticated user's id and role available on the gin context as
+// "userID" and "userRole".
+func Required() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" || !strings.HasPrefix(header, "Bearer ") {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Missing or malformed Authorization header",
+			})
+			return
+		}
+
+		claims, err := ParseToken(strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired token",
+			})
+			return
+		}
+
+		userID, err := strconv.Atoi(claims.Subject)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid token subject",
+			})
+			return
+		}
+
+		c.Set("userID", userID)
+		c.Set("userRole", claims.Role)
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests whose authenticated user does not have the
+// given role. It must be chained after Required().
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, exists := c.Get("userRole")
+		if !exists || userRole != role {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Insufficient permissions",
+			})
+			return
+		}
+		c.Next()
+	}
+}
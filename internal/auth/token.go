@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	Issuer          = "device-monitor"
+	Audience        = "device-monitor-api"
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// Claims is the JWT payload issued for both access and refresh tokens.
+// RegisteredClaims.ID (jti) is what refresh tokens are tracked and
+// revoked by in the refresh_tokens table.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+func jwtSecret() []byte {
+	secret := os.Getenv("AUTH_JWT_SECRET")
+	if secret == "" {
+		secret = "change-me-in-production"
+	}
+	return []byte(secret)
+}
+
+// GenerateAccessToken issues a short-lived token for an authenticated user.
+func GenerateAccessToken(userID int, role string) (string, error) {
+	token, _, err := generateToken(userID, role, AccessTokenTTL)
+	return token, err
+}
+
+// GenerateRefreshToken issues a longer-lived token and returns its jti so
+// callers can persist it for later revocation.
+func GenerateRefreshToken(userID int, role string) (token string, jti string, err error) {
+	return generateToken(userID, role, RefreshTokenTTL)
+}
+
+func generateToken(userID int, role string, ttl time.Duration) (string, string, error) {
+	now := time.Now()
+	jti := uuid.NewString()
+
+	claims := Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   fmt.Sprintf("%d", userID),
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{Audience},
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret())
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, jti, nil
+}
+
+// ParseToken validates a token's signature, issuer, audience and
+// expiration, and returns its claims.
+func ParseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return jwtSecret(), nil
+	}, jwt.WithIssuer(Issuer), jwt.WithAudience(Audience))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,581 @@
+package devices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/dsrose15/device-monitor/internal/api"
+	"github.com/dsrose15/device-monitor/internal/observability"
+	"github.com/dsrose15/device-monitor/internal/webhooks"
+)
+
+var deviceAllowedColumns = []string{"id", "name", "type", "owner_id", "created_at", "updated_at"}
+
+type Handlers struct {
+	DB *pgxpool.Pool
+}
+
+func NewHandlers(db *pgxpool.Pool) *Handlers {
+	return &Handlers{DB: db}
+}
+
+func scanDeviceTags(tagsRaw []byte, device *Device) error {
+	if len(tagsRaw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(tagsRaw, &device.Tags)
+}
+
+// deviceOwnedBy reports whether deviceID exists and belongs to ownerID.
+func (h *Handlers) deviceOwnedBy(ctx context.Context, deviceID, ownerID int) (bool, error) {
+	var owned bool
+	err := h.DB.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM devices WHERE id = $1 AND owner_id = $2)`, deviceID, ownerID).Scan(&owned)
+	return owned, err
+}
+
+// Create a new device
+func (h *Handlers) CreateDevice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ownerID := c.GetInt("userID")
+
+	var req struct {
+		Name string            `json:"name" binding:"required"`
+		Type string            `json:"type" binding:"required"`
+		Tags map[string]string `json:"tags"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	tagsJSON, err := json.Marshal(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid tags",
+		})
+		return
+	}
+
+	var device Device
+	var tagsRaw []byte
+	query := `INSERT INTO devices (name, type, owner_id, tags, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, NOW(), NOW())
+			  RETURNING id, name, type, owner_id, tags, last_seen, created_at, updated_at`
+
+	err = h.DB.QueryRow(ctx, query, req.Name, req.Type, ownerID, tagsJSON).Scan(
+		&device.ID, &device.Name, &device.Type, &device.OwnerID, &tagsRaw, &device.LastSeen, &device.CreatedAt, &device.UpdatedAt,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create device",
+		})
+		return
+	}
+
+	if err := scanDeviceTags(tagsRaw, &device); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to decode device tags",
+		})
+		return
+	}
+
+	if err := webhooks.Emit(ctx, h.DB, webhooks.EventDeviceCreated, device); err != nil {
+		log.Printf("webhooks: failed to emit device.created event: %v", err)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Device created successfully",
+		"device":  device,
+	})
+}
+
+// Get all devices owned by the authenticated user
+func (h *Handlers) GetDevices(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ownerID := c.GetInt("userID")
+
+	params, err := api.ParsePageParams(c, deviceAllowedColumns, "created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := `SELECT id, name, type, owner_id, tags, last_seen, created_at, updated_at FROM devices WHERE owner_id = $1`
+	args := []any{ownerID}
+
+	filterClause, filterArgs := api.BuildWhereClause(params.Filters, len(args))
+	query += filterClause
+	args = append(args, filterArgs...)
+
+	cursorClause, cursorArgs := api.CursorClause(params.Cursor, params.SortOrder, len(args))
+	query += cursorClause
+	args = append(args, cursorArgs...)
+
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d", params.SortColumn, params.SortOrder, params.SortOrder, len(args)+1)
+	args = append(args, params.Limit+1)
+
+	rows, err := h.DB.Query(ctx, query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch devices",
+		})
+		return
+	}
+	defer rows.Close()
+
+	var devices []Device
+	for rows.Next() {
+		var device Device
+		var tagsRaw []byte
+		err := rows.Scan(&device.ID, &device.Name, &device.Type, &device.OwnerID, &tagsRaw, &device.LastSeen, &device.CreatedAt, &device.UpdatedAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to scan device data",
+			})
+			return
+		}
+		if err := scanDeviceTags(tagsRaw, &device); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to decode device tags",
+			})
+			return
+		}
+		devices = append(devices, device)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error iterating over rows",
+		})
+		return
+	}
+
+	hasMore := len(devices) > params.Limit
+	if hasMore {
+		devices = devices[:params.Limit]
+	}
+
+	var nextCursor *string
+	if hasMore && len(devices) > 0 {
+		last := devices[len(devices)-1]
+		token, err := api.EncodeCursor(api.Cursor{LastCreatedAt: last.CreatedAt, LastID: last.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to encode next cursor",
+			})
+			return
+		}
+		nextCursor = &token
+	}
+
+	c.JSON(http.StatusOK, api.PageResult{
+		Data:       devices,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	})
+}
+
+// Get device by ID
+func (h *Handlers) GetDevice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ownerID := c.GetInt("userID")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	var device Device
+	var tagsRaw []byte
+	query := `SELECT id, name, type, owner_id, tags, last_seen, created_at, updated_at FROM devices WHERE id = $1 AND owner_id = $2`
+	err = h.DB.QueryRow(ctx, query, id, ownerID).Scan(&device.ID, &device.Name, &device.Type, &device.OwnerID, &tagsRaw, &device.LastSeen, &device.CreatedAt, &device.UpdatedAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Device not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch device",
+		})
+		return
+	}
+
+	if err := scanDeviceTags(tagsRaw, &device); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to decode device tags",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"device": device,
+	})
+}
+
+// Update device
+func (h *Handlers) UpdateDevice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ownerID := c.GetInt("userID")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	var req struct {
+		Name string            `json:"name" binding:"required"`
+		Type string            `json:"type" binding:"required"`
+		Tags map[string]string `json:"tags"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	tagsJSON, err := json.Marshal(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid tags",
+		})
+		return
+	}
+
+	var device Device
+	var tagsRaw []byte
+	query := `UPDATE devices SET name = $1, type = $2, tags = $3, updated_at = NOW()
+			  WHERE id = $4 AND owner_id = $5
+			  RETURNING id, name, type, owner_id, tags, last_seen, created_at, updated_at`
+
+	err = h.DB.QueryRow(ctx, query, req.Name, req.Type, tagsJSON, id, ownerID).Scan(
+		&device.ID, &device.Name, &device.Type, &device.OwnerID, &tagsRaw, &device.LastSeen, &device.CreatedAt, &device.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Device not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update device",
+		})
+		return
+	}
+
+	if err := scanDeviceTags(tagsRaw, &device); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to decode device tags",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Device updated successfully",
+		"device":  device,
+	})
+}
+
+// Delete device
+func (h *Handlers) DeleteDevice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ownerID := c.GetInt("userID")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	result, err := h.DB.Exec(ctx, "DELETE FROM devices WHERE id = $1 AND owner_id = $2", id, ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete device",
+		})
+		return
+	}
+
+	if result.RowsAffected() == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Device not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Device deleted successfully",
+	})
+}
+
+// Batch ingest telemetry samples for a device
+func (h *Handlers) IngestTelemetry(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ownerID := c.GetInt("userID")
+
+	deviceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	owned, err := h.deviceOwnedBy(ctx, deviceID, ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to look up device",
+		})
+		return
+	}
+	if !owned {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Device not found",
+		})
+		return
+	}
+
+	var samples []struct {
+		Ts     time.Time         `json:"ts" binding:"required"`
+		Metric string            `json:"metric" binding:"required"`
+		Value  float64           `json:"value"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	if err := c.ShouldBindJSON(&samples); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if len(samples) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "No samples provided",
+		})
+		return
+	}
+
+	batch := &pgx.Batch{}
+	for _, sample := range samples {
+		labelsJSON, err := json.Marshal(sample.Labels)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid labels",
+			})
+			return
+		}
+		batch.Queue(
+			`INSERT INTO telemetry (device_id, ts, metric, value, labels) VALUES ($1, $2, $3, $4, $5)`,
+			deviceID, sample.Ts, sample.Metric, sample.Value, labelsJSON,
+		)
+	}
+
+	br := h.DB.SendBatch(ctx, batch)
+	for range samples {
+		if _, err := br.Exec(); err != nil {
+			br.Close()
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to ingest telemetry",
+			})
+			return
+		}
+	}
+	if err := br.Close(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to ingest telemetry",
+		})
+		return
+	}
+
+	if _, err := h.DB.Exec(ctx, `UPDATE devices SET last_seen = NOW() WHERE id = $1`, deviceID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update device last_seen",
+		})
+		return
+	}
+
+	// Subscribers are fanned out solely from the stream.Listen NOTIFY loop
+	// (started in main), not from here, so every instance - including this
+	// one - delivers each sample exactly once instead of double-publishing
+	// it locally.
+	observability.TelemetryIngestedTotal.Add(float64(len(samples)))
+
+	for _, sample := range samples {
+		err := webhooks.EvaluateThresholds(ctx, h.DB, webhooks.ThresholdSample{
+			DeviceID: deviceID,
+			Metric:   sample.Metric,
+			Value:    sample.Value,
+		})
+		if err != nil {
+			log.Printf("webhooks: failed to evaluate thresholds for device %d: %v", deviceID, err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Telemetry ingested successfully",
+		"ingested": len(samples),
+	})
+}
+
+// Query telemetry for a device, aggregated into time buckets
+func (h *Handlers) QueryTelemetry(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ownerID := c.GetInt("userID")
+
+	deviceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	owned, err := h.deviceOwnedBy(ctx, deviceID, ownerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to look up device",
+		})
+		return
+	}
+	if !owned {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Device not found",
+		})
+		return
+	}
+
+	metric := c.Query("metric")
+	if metric == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "metric query parameter is required",
+		})
+		return
+	}
+
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		to, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid to timestamp, expected RFC3339",
+			})
+			return
+		}
+	}
+
+	from := to.Add(-1 * time.Hour)
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid from timestamp, expected RFC3339",
+			})
+			return
+		}
+	}
+
+	step := 1 * time.Minute
+	if stepStr := c.Query("step"); stepStr != "" {
+		step, err = time.ParseDuration(stepStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid step, expected a Go duration like 30s or 5m",
+			})
+			return
+		}
+	}
+	if step < time.Second {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "step must be at least 1 second",
+		})
+		return
+	}
+	stepInterval := fmt.Sprintf("%d seconds", int(step.Seconds()))
+
+	query := `SELECT date_bin($1::interval, ts, $2::timestamptz) AS bucket,
+					 avg(value) AS avg,
+					 min(value) AS min,
+					 max(value) AS max,
+					 (array_agg(value ORDER BY ts DESC))[1] AS last
+			  FROM telemetry
+			  WHERE device_id = $3 AND metric = $4 AND ts >= $5 AND ts <= $6
+			  GROUP BY bucket
+			  ORDER BY bucket`
+
+	rows, err := h.DB.Query(ctx, query, stepInterval, from, deviceID, metric, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to query telemetry",
+		})
+		return
+	}
+	defer rows.Close()
+
+	var buckets []TelemetryBucket
+	for rows.Next() {
+		var bucket TelemetryBucket
+		if err := rows.Scan(&bucket.Bucket, &bucket.Avg, &bucket.Min, &bucket.Max, &bucket.Last); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to scan telemetry bucket",
+			})
+			return
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error iterating over rows",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"metric": metric,
+		"from":   from,
+		"to":     to,
+		"step":   step.String(),
+		"data":   buckets,
+	})
+}
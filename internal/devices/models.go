@@ -0,0 +1,32 @@
+package devices
+
+import "time"
+
+type Device struct {
+	ID        int               `json:"id" db:"id"`
+	Name      string            `json:"name" db:"name"`
+	Type      string            `json:"type" db:"type"`
+	OwnerID   int               `json:"owner_id" db:"owner_id"`
+	Tags      map[string]string `json:"tags" db:"tags"`
+	LastSeen  *time.Time        `json:"last_seen" db:"last_seen"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+type Telemetry struct {
+	ID       int64             `json:"id" db:"id"`
+	DeviceID int               `json:"device_id" db:"device_id"`
+	Ts       time.Time         `json:"ts" db:"ts"`
+	Metric   string            `json:"metric" db:"metric"`
+	Value    float64           `json:"value" db:"value"`
+	Labels   map[string]string `json:"labels" db:"labels"`
+}
+
+// TelemetryBucket is one row of a time-bucketed aggregation query.
+type TelemetryBucket struct {
+	Bucket time.Time `json:"bucket"`
+	Avg    float64   `json:"avg"`
+	Min    float64   `json:"min"`
+	Max    float64   `json:"max"`
+	Last   float64   `json:"last"`
+}
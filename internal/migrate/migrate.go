@@ -0,0 +1,60 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Apply applies any migrations in fsys that have not yet been recorded in
+// schema_migrations, in filename order. It is safe to call on every
+// startup; already-applied migrations are skipped.
+func Apply(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS) error {
+	if _, err := pool.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var applied bool
+		err := pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, name).Scan(&applied)
+		if err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", name, err)
+		}
+		if applied {
+			continue
+		}
+
+		sql, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		if _, err := pool.Exec(ctx, string(sql)); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", name, err)
+		}
+
+		if _, err := pool.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, name); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
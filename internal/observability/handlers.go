@@ -0,0 +1,92 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type Handlers struct {
+	DB       *pgxpool.Pool
+	MinConns int32
+}
+
+func NewHandlers(db *pgxpool.Pool, minConns int32) *Handlers {
+	return &Handlers{DB: db, MinConns: minConns}
+}
+
+// Livez reports whether the process itself is up. It never touches a
+// dependency, so it only fails if the process can't serve requests at all.
+func (h *Handlers) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// Readyz reports whether the service is ready to serve traffic: the
+// database must be reachable, the pool must have at least MinConns
+// connections established, and schema_migrations must be readable. It
+// reports the most recently applied migration version; it does not check
+// that version against the migrations embedded in the binary.
+func (h *Handlers) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+	defer cancel()
+
+	checks := gin.H{}
+	ready := true
+
+	if err := h.DB.Ping(ctx); err != nil {
+		checks["database"] = gin.H{"status": "fail", "error": err.Error()}
+		ready = false
+	} else {
+		checks["database"] = gin.H{"status": "ok"}
+	}
+
+	stat := h.DB.Stat()
+	ObservePoolStats(stat)
+	if stat.TotalConns() < h.MinConns {
+		checks["pool"] = gin.H{
+			"status":      "fail",
+			"total_conns": stat.TotalConns(),
+			"min_conns":   h.MinConns,
+		}
+		ready = false
+	} else {
+		checks["pool"] = gin.H{
+			"status":      "ok",
+			"total_conns": stat.TotalConns(),
+			"idle_conns":  stat.IdleConns(),
+		}
+	}
+
+	var migrationVersion string
+	err := h.DB.QueryRow(ctx, `SELECT version FROM schema_migrations ORDER BY applied_at DESC LIMIT 1`).Scan(&migrationVersion)
+	if err != nil {
+		checks["migrations"] = gin.H{"status": "fail", "error": err.Error()}
+		ready = false
+	} else {
+		checks["migrations"] = gin.H{"status": "ok", "version": migrationVersion}
+	}
+
+	status := http.StatusOK
+	overall := "ok"
+	if !ready {
+		status = http.StatusServiceUnavailable
+		overall = "unavailable"
+	}
+
+	c.JSON(status, gin.H{
+		"status": overall,
+		"checks": checks,
+	})
+}
+
+// Metrics serves the process's Prometheus metrics.
+func (h *Handlers) Metrics(c *gin.Context) {
+	ObservePoolStats(h.DB.Stat())
+	promhttp.Handler().ServeHTTP(c.Writer, c.Request)
+}
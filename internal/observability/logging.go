@@ -0,0 +1,11 @@
+package observability
+
+import (
+	"log/slog"
+	"os"
+)
+
+// NewLogger returns the process-wide structured JSON logger.
+func NewLogger() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(os.Stdout, nil))
+}
@@ -0,0 +1,50 @@
+package observability
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total HTTP requests processed, labeled by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, labeled by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	TelemetryIngestedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "telemetry_ingested_total",
+		Help: "Total telemetry samples successfully ingested.",
+	})
+
+	PoolAcquireCount = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "pgx_pool_acquire_count",
+		Help: "Cumulative count of successful connection acquisitions from the pool.",
+	})
+
+	PoolIdleConns = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "pgx_pool_idle_conns",
+		Help: "Number of currently idle connections in the pool.",
+	})
+
+	PoolTotalConns = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "pgx_pool_total_conns",
+		Help: "Total number of connections currently in the pool.",
+	})
+)
+
+// ObservePoolStats refreshes the pool gauges from a live pgxpool.Stat
+// snapshot. pgx does not push these updates itself, so callers should
+// invoke this right before anything reads the gauges (e.g. on every
+// /readyz or /metrics request).
+func ObservePoolStats(stat *pgxpool.Stat) {
+	PoolAcquireCount.Set(float64(stat.AcquireCount()))
+	PoolIdleConns.Set(float64(stat.IdleConns()))
+	PoolTotalConns.Set(float64(stat.TotalConns()))
+}
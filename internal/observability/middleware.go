@@ -0,0 +1,57 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// Middleware records Prometheus metrics for every request and emits a
+// structured JSON log line, propagating a request id through the
+// request's context so downstream DB queries can be traced end-to-end.
+func Middleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Writer.Header().Set("X-Request-ID", requestID)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDKey, requestID))
+
+		start := time.Now()
+		c.Next()
+		duration := time.Since(start)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		HTTPRequestsTotal.WithLabelValues(c.Request.Method, route, status).Inc()
+		HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(duration.Seconds())
+
+		logger.LogAttrs(c.Request.Context(), slog.LevelInfo, "http_request",
+			slog.String("request_id", requestID),
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("duration", duration),
+		)
+	}
+}
+
+// RequestID extracts the request id propagated by Middleware, or "" if
+// ctx did not originate from a request that passed through it.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
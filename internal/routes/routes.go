@@ -5,28 +5,79 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/dsrose15/device-monitor/internal/api"
+	"github.com/dsrose15/device-monitor/internal/auth"
+	"github.com/dsrose15/device-monitor/internal/devices"
+	"github.com/dsrose15/device-monitor/internal/observability"
+	"github.com/dsrose15/device-monitor/internal/stream"
+	"github.com/dsrose15/device-monitor/internal/webhooks"
 )
 
-func SetupRoutes(router *gin.Engine, db *pgxpool.Pool) {
+func SetupRoutes(router *gin.Engine, db *pgxpool.Pool, hub *stream.Hub, minConns int32) {
 	// Initialize handlers
 	h := api.NewHandlers(db)
+	dh := devices.NewHandlers(db)
+	ah := auth.NewHandlers(db)
+	sh := stream.NewHandlers(hub)
+	oh := observability.NewHandlers(db, minConns)
+	wh := webhooks.NewHandlers(db)
 
-	// Health check route
-	router.GET("/health", h.HealthCheck)
+	// Structured request logging and Prometheus HTTP metrics
+	router.Use(observability.Middleware(observability.NewLogger()))
+
+	// Liveness, readiness and metrics routes
+	router.GET("/livez", oh.Livez)
+	router.GET("/readyz", oh.Readyz)
+	router.GET("/metrics", oh.Metrics)
 
 	// API routes
 	api := router.Group("/api/v1")
 	{
-		// User routes
-		users := api.Group("/users")
+		// Auth routes
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.POST("/login", ah.Login)
+			authRoutes.POST("/refresh", ah.Refresh)
+			authRoutes.POST("/logout", ah.Logout)
+		}
+
+		// User routes. Registration is left open (no deployment has a user
+		// yet to authenticate as), everything else requires a session.
+		api.POST("/users", h.CreateUser)
+
+		users := api.Group("/users", auth.Required())
 		{
 			users.GET("", h.GetUsers)
 			users.GET("/:id", h.GetUser)
-			users.POST("", h.CreateUser)
 			users.PUT("/:id", h.UpdateUser)
 			users.DELETE("/:id", h.DeleteUser)
 		}
 
+		// Device routes
+		deviceRoutes := api.Group("/devices", auth.Required())
+		{
+			deviceRoutes.GET("", dh.GetDevices)
+			deviceRoutes.GET("/:id", dh.GetDevice)
+			deviceRoutes.POST("", dh.CreateDevice)
+			deviceRoutes.PUT("/:id", dh.UpdateDevice)
+			deviceRoutes.DELETE("/:id", dh.DeleteDevice)
+			deviceRoutes.POST("/:id/telemetry", dh.IngestTelemetry)
+			deviceRoutes.GET("/:id/telemetry", dh.QueryTelemetry)
+			deviceRoutes.GET("/:id/stream", sh.Stream)
+			deviceRoutes.POST("/:id/threshold-rules", wh.CreateThresholdRule)
+			deviceRoutes.GET("/:id/threshold-rules", wh.GetThresholdRules)
+			deviceRoutes.DELETE("/:id/threshold-rules/:ruleId", wh.DeleteThresholdRule)
+		}
+
+		// Webhook routes
+		webhookRoutes := api.Group("/webhooks", auth.Required())
+		{
+			webhookRoutes.GET("", wh.GetWebhooks)
+			webhookRoutes.GET("/:id", wh.GetWebhook)
+			webhookRoutes.POST("", wh.CreateWebhook)
+			webhookRoutes.PUT("/:id", wh.UpdateWebhook)
+			webhookRoutes.DELETE("/:id", wh.DeleteWebhook)
+		}
+
 		// Example additional routes
 		api.GET("/ping", h.Ping)
 	}
@@ -0,0 +1,93 @@
+package stream
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+type Handlers struct {
+	Hub *Hub
+}
+
+func NewHandlers(hub *Hub) *Handlers {
+	return &Handlers{Hub: hub}
+}
+
+type controlFrame struct {
+	Op      string   `json:"op"`
+	Metrics []string `json:"metrics"`
+}
+
+// Stream upgrades the request to a WebSocket and pushes telemetry samples
+// for the device in the URL as they are published to the hub. Clients
+// narrow what they receive by sending a
+// {"op":"subscribe","metrics":[...]} control frame; an empty or omitted
+// metrics list receives every metric for the device.
+func (h *Handlers) Stream(c *gin.Context) {
+	deviceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	samples, unsubscribe := h.Hub.Subscribe(deviceID)
+	defer unsubscribe()
+
+	var metricsMu sync.RWMutex
+	var allowedMetrics map[string]struct{}
+
+	go func() {
+		for {
+			var frame controlFrame
+			if err := conn.ReadJSON(&frame); err != nil {
+				return
+			}
+			if frame.Op != "subscribe" {
+				continue
+			}
+
+			set := make(map[string]struct{}, len(frame.Metrics))
+			for _, metric := range frame.Metrics {
+				set[metric] = struct{}{}
+			}
+
+			metricsMu.Lock()
+			allowedMetrics = set
+			metricsMu.Unlock()
+		}
+	}()
+
+	for sample := range samples {
+		metricsMu.RLock()
+		allowed := allowedMetrics
+		metricsMu.RUnlock()
+
+		if len(allowed) > 0 {
+			if _, ok := allowed[sample.Metric]; !ok {
+				continue
+			}
+		}
+
+		if err := conn.WriteJSON(sample); err != nil {
+			return
+		}
+	}
+}
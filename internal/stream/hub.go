@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+// outboundBuffer bounds how many unsent samples a subscriber can queue
+// before it is treated as a slow consumer and dropped.
+const outboundBuffer = 32
+
+// Sample is a telemetry reading fanned out to stream subscribers.
+type Sample struct {
+	DeviceID int               `json:"device_id"`
+	Ts       time.Time         `json:"ts"`
+	Metric   string            `json:"metric"`
+	Value    float64           `json:"value"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+type subscriber struct {
+	ch chan Sample
+}
+
+// Hub is an in-process pub/sub broker that fans telemetry samples out to
+// WebSocket connections subscribed to a given device.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[int]map[*subscriber]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[int]map[*subscriber]struct{})}
+}
+
+// Subscribe registers a new subscriber for deviceID and returns a channel
+// of samples for it along with an unsubscribe function the caller must
+// invoke when done. The channel is closed if Publish drops the subscriber
+// as a slow consumer.
+func (h *Hub) Subscribe(deviceID int) (<-chan Sample, func()) {
+	sub := &subscriber{ch: make(chan Sample, outboundBuffer)}
+
+	h.mu.Lock()
+	if h.subscribers[deviceID] == nil {
+		h.subscribers[deviceID] = make(map[*subscriber]struct{})
+	}
+	h.subscribers[deviceID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		delete(h.subscribers[deviceID], sub)
+		if len(h.subscribers[deviceID]) == 0 {
+			delete(h.subscribers, deviceID)
+		}
+		h.mu.Unlock()
+	}
+
+	return sub.ch, unsubscribe
+}
+
+// Publish fans sample out to every current subscriber of deviceID. A
+// subscriber whose outbound channel is full is dropped as a slow consumer
+// (removed from the hub and its channel closed) rather than blocking the
+// publisher.
+func (h *Hub) Publish(deviceID int, sample Sample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers[deviceID] {
+		select {
+		case sub.ch <- sample:
+		default:
+			// slow consumer, drop the subscriber
+			delete(h.subscribers[deviceID], sub)
+			close(sub.ch)
+		}
+	}
+	if len(h.subscribers[deviceID]) == 0 {
+		delete(h.subscribers, deviceID)
+	}
+}
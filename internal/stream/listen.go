@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// notification mirrors the JSON payload emitted by the telemetry_notify
+// trigger via pg_notify.
+type notification struct {
+	DeviceID int               `json:"device_id"`
+	Ts       time.Time         `json:"ts"`
+	Metric   string            `json:"metric"`
+	Value    float64           `json:"value"`
+	Labels   map[string]string `json:"labels"`
+}
+
+// Listen dedicates a pool connection to LISTEN device_telemetry and
+// republishes every NOTIFY payload onto hub, so that every app instance
+// behind a load balancer receives samples ingested by any instance. It
+// blocks until ctx is canceled or the connection is irrecoverably lost.
+func Listen(ctx context.Context, pool *pgxpool.Pool, hub *Hub) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN device_telemetry"); err != nil {
+		return err
+	}
+
+	for {
+		notif, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var payload notification
+		if err := json.Unmarshal([]byte(notif.Payload), &payload); err != nil {
+			log.Printf("stream: failed to decode telemetry notification: %v", err)
+			continue
+		}
+
+		hub.Publish(payload.DeviceID, Sample{
+			DeviceID: payload.DeviceID,
+			Ts:       payload.Ts,
+			Metric:   payload.Metric,
+			Value:    payload.Value,
+			Labels:   payload.Labels,
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	dispatchPollInterval = 2 * time.Second
+	dispatchBatchSize    = 20
+
+	// processingVisibilityTimeout bounds how long a delivery may sit in
+	// StatusProcessing. A row that's been claimed longer than this without
+	// reaching a terminal state means the instance that claimed it crashed
+	// or its delivery Exec failed, so it's returned to pending for another
+	// pass to retry instead of being wedged forever.
+	processingVisibilityTimeout = 5 * time.Minute
+)
+
+type claimedDelivery struct {
+	id       int64
+	event    string
+	payload  []byte
+	attempts int
+	url      string
+	secret   string
+}
+
+// RunDispatcher polls webhook_deliveries for due rows and delivers them,
+// retrying with exponential backoff and jitter up to maxDeliveryAttempts
+// before marking a delivery dead. It blocks until ctx is canceled.
+func RunDispatcher(ctx context.Context, pool *pgxpool.Pool) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(dispatchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := dispatchDueDeliveries(ctx, pool, client); err != nil {
+				log.Printf("webhooks: dispatch pass failed: %v", err)
+			}
+		}
+	}
+}
+
+// reclaimStaleProcessingDeliveries returns deliveries stuck in
+// StatusProcessing past processingVisibilityTimeout back to StatusPending,
+// so a crash (or a failed markFailed/markDelivered Exec) between the claim
+// and a terminal status doesn't drop the delivery forever.
+func reclaimStaleProcessingDeliveries(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx,
+		`UPDATE webhook_deliveries SET status = $1
+		 WHERE status = $2 AND claimed_at < NOW() - $3::interval`,
+		StatusPending, StatusProcessing, fmt.Sprintf("%d seconds", int(processingVisibilityTimeout.Seconds())),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reclaim stale processing deliveries: %w", err)
+	}
+	return nil
+}
+
+// dispatchDueDeliveries claims a batch of due deliveries with
+// SELECT ... FOR UPDATE SKIP LOCKED (so multiple app instances running the
+// dispatcher don't deliver the same row twice), marks them processing, then
+// delivers each one outside the transaction.
+func dispatchDueDeliveries(ctx context.Context, pool *pgxpool.Pool, client *http.Client) error {
+	if err := reclaimStaleProcessingDeliveries(ctx, pool); err != nil {
+		return err
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin dispatch transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT d.id, d.event, d.payload, d.attempts, w.url, w.secret
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = $1 AND d.next_attempt_at <= NOW()
+		ORDER BY d.next_attempt_at
+		LIMIT $2
+		FOR UPDATE OF d SKIP LOCKED`, StatusPending, dispatchBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim due deliveries: %w", err)
+	}
+
+	var due []claimedDelivery
+	for rows.Next() {
+		var d claimedDelivery
+		if err := rows.Scan(&d.id, &d.event, &d.payload, &d.attempts, &d.url, &d.secret); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan due delivery: %w", err)
+		}
+		due = append(due, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(due) > 0 {
+		ids := make([]int64, len(due))
+		for i, d := range due {
+			ids[i] = d.id
+		}
+		if _, err := tx.Exec(ctx, `UPDATE webhook_deliveries SET status = $1, claimed_at = NOW() WHERE id = ANY($2)`, StatusProcessing, ids); err != nil {
+			return fmt.Errorf("failed to mark deliveries processing: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit delivery claim: %w", err)
+	}
+
+	for _, d := range due {
+		deliver(ctx, pool, client, d)
+	}
+
+	return nil
+}
+
+func deliver(ctx context.Context, pool *pgxpool.Pool, client *http.Client, d claimedDelivery) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(d.payload))
+	if err != nil {
+		markFailed(ctx, pool, d, err.Error())
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", sign(d.secret, d.payload))
+	req.Header.Set("X-Webhook-Event", d.event)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		markFailed(ctx, pool, d, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if _, err := pool.Exec(ctx, `UPDATE webhook_deliveries SET status = $1 WHERE id = $2`, StatusDelivered, d.id); err != nil {
+			log.Printf("webhooks: failed to mark delivery %d delivered: %v", d.id, err)
+		}
+		return
+	}
+
+	markFailed(ctx, pool, d, fmt.Sprintf("unexpected status code %d", resp.StatusCode))
+}
+
+// markFailed bumps the attempt count and either marks the delivery dead, if
+// it has exhausted maxDeliveryAttempts, or reschedules it with exponential
+// backoff and jitter.
+func markFailed(ctx context.Context, pool *pgxpool.Pool, d claimedDelivery, reason string) {
+	attempts := d.attempts + 1
+	if attempts >= maxDeliveryAttempts {
+		if _, err := pool.Exec(ctx, `UPDATE webhook_deliveries SET status = $1, attempts = $2 WHERE id = $3`, StatusDead, attempts, d.id); err != nil {
+			log.Printf("webhooks: failed to mark delivery %d dead: %v", d.id, err)
+		}
+		log.Printf("webhooks: delivery %d exhausted retries: %s", d.id, reason)
+		return
+	}
+
+	backoff := time.Duration(1<<uint(attempts)) * time.Second
+	jitter := time.Duration(rand.Int63n(int64(backoff)/2 + 1))
+	nextAttemptAt := time.Now().Add(backoff + jitter)
+
+	if _, err := pool.Exec(ctx,
+		`UPDATE webhook_deliveries SET status = $1, attempts = $2, next_attempt_at = $3 WHERE id = $4`,
+		StatusPending, attempts, nextAttemptAt, d.id,
+	); err != nil {
+		log.Printf("webhooks: failed to reschedule delivery %d: %v", d.id, err)
+	}
+}
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
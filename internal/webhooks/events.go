@@ -0,0 +1,50 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Emit enqueues a delivery for every webhook subscribed to eventType.
+// Deliveries are claimed and sent asynchronously by the dispatcher started
+// in main.go, so Emit itself never makes an outbound request.
+func Emit(ctx context.Context, pool *pgxpool.Pool, eventType string, payload any) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	rows, err := pool.Query(ctx, `SELECT id FROM webhooks WHERE $1 = ANY(event_types)`, eventType)
+	if err != nil {
+		return fmt.Errorf("failed to look up subscribed webhooks: %w", err)
+	}
+
+	var webhookIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan webhook id: %w", err)
+		}
+		webhookIDs = append(webhookIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, webhookID := range webhookIDs {
+		_, err := pool.Exec(ctx,
+			`INSERT INTO webhook_deliveries (webhook_id, event, payload) VALUES ($1, $2, $3)`,
+			webhookID, eventType, payloadJSON,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to enqueue delivery for webhook %d: %w", webhookID, err)
+		}
+	}
+
+	return nil
+}
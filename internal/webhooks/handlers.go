@@ -0,0 +1,292 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/dsrose15/device-monitor/internal/api"
+)
+
+var webhookAllowedColumns = []string{"id", "url", "created_at", "updated_at"}
+
+type Handlers struct {
+	DB *pgxpool.Pool
+}
+
+func NewHandlers(db *pgxpool.Pool) *Handlers {
+	return &Handlers{DB: db}
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Register a webhook subscription for the authenticated user
+func (h *Handlers) CreateWebhook(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	userID := c.GetInt("userID")
+
+	var req struct {
+		URL        string   `json:"url" binding:"required,url"`
+		EventTypes []string `json:"event_types" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create webhook",
+		})
+		return
+	}
+
+	var webhook Webhook
+	query := `INSERT INTO webhooks (user_id, url, secret, event_types, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, NOW(), NOW())
+			  RETURNING id, user_id, url, event_types, created_at, updated_at`
+
+	err = h.DB.QueryRow(ctx, query, userID, req.URL, secret, req.EventTypes).Scan(
+		&webhook.ID, &webhook.UserID, &webhook.URL, &webhook.EventTypes, &webhook.CreatedAt, &webhook.UpdatedAt,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create webhook",
+		})
+		return
+	}
+
+	// The secret is only ever returned here, at creation time; it is never
+	// included in subsequent responses.
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook created successfully",
+		"webhook": webhook,
+		"secret":  secret,
+	})
+}
+
+// Get all webhooks owned by the authenticated user
+func (h *Handlers) GetWebhooks(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	userID := c.GetInt("userID")
+
+	params, err := api.ParsePageParams(c, webhookAllowedColumns, "created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := `SELECT id, user_id, url, event_types, created_at, updated_at FROM webhooks WHERE user_id = $1`
+	args := []any{userID}
+
+	filterClause, filterArgs := api.BuildWhereClause(params.Filters, len(args))
+	query += filterClause
+	args = append(args, filterArgs...)
+
+	cursorClause, cursorArgs := api.CursorClause(params.Cursor, params.SortOrder, len(args))
+	query += cursorClause
+	args = append(args, cursorArgs...)
+
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d", params.SortColumn, params.SortOrder, params.SortOrder, len(args)+1)
+	args = append(args, params.Limit+1)
+
+	rows, err := h.DB.Query(ctx, query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch webhooks",
+		})
+		return
+	}
+	defer rows.Close()
+
+	var hooks []Webhook
+	for rows.Next() {
+		var hook Webhook
+		if err := rows.Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.EventTypes, &hook.CreatedAt, &hook.UpdatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to scan webhook data",
+			})
+			return
+		}
+		hooks = append(hooks, hook)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error iterating over rows",
+		})
+		return
+	}
+
+	hasMore := len(hooks) > params.Limit
+	if hasMore {
+		hooks = hooks[:params.Limit]
+	}
+
+	var nextCursor *string
+	if hasMore && len(hooks) > 0 {
+		last := hooks[len(hooks)-1]
+		token, err := api.EncodeCursor(api.Cursor{LastCreatedAt: last.CreatedAt, LastID: last.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to encode next cursor",
+			})
+			return
+		}
+		nextCursor = &token
+	}
+
+	c.JSON(http.StatusOK, api.PageResult{
+		Data:       hooks,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	})
+}
+
+// Get a webhook owned by the authenticated user
+func (h *Handlers) GetWebhook(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	userID := c.GetInt("userID")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID",
+		})
+		return
+	}
+
+	var hook Webhook
+	query := `SELECT id, user_id, url, event_types, created_at, updated_at FROM webhooks WHERE id = $1 AND user_id = $2`
+	err = h.DB.QueryRow(ctx, query, id, userID).Scan(&hook.ID, &hook.UserID, &hook.URL, &hook.EventTypes, &hook.CreatedAt, &hook.UpdatedAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Webhook not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch webhook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook": hook,
+	})
+}
+
+// Update a webhook's URL and subscribed event types
+func (h *Handlers) UpdateWebhook(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	userID := c.GetInt("userID")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID",
+		})
+		return
+	}
+
+	var req struct {
+		URL        string   `json:"url" binding:"required,url"`
+		EventTypes []string `json:"event_types" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var hook Webhook
+	query := `UPDATE webhooks SET url = $1, event_types = $2, updated_at = NOW()
+			  WHERE id = $3 AND user_id = $4
+			  RETURNING id, user_id, url, event_types, created_at, updated_at`
+
+	err = h.DB.QueryRow(ctx, query, req.URL, req.EventTypes, id, userID).Scan(
+		&hook.ID, &hook.UserID, &hook.URL, &hook.EventTypes, &hook.CreatedAt, &hook.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Webhook not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update webhook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook updated successfully",
+		"webhook": hook,
+	})
+}
+
+// Delete a webhook owned by the authenticated user
+func (h *Handlers) DeleteWebhook(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	userID := c.GetInt("userID")
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID",
+		})
+		return
+	}
+
+	result, err := h.DB.Exec(ctx, "DELETE FROM webhooks WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete webhook",
+		})
+		return
+	}
+
+	if result.RowsAffected() == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Webhook not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook deleted successfully",
+	})
+}
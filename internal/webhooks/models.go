@@ -0,0 +1,53 @@
+package webhooks
+
+import "time"
+
+// Event types that a webhook can subscribe to.
+const (
+	EventDeviceCreated            = "device.created"
+	EventDeviceOffline            = "device.offline"
+	EventTelemetryThresholdBreach = "telemetry.threshold_breached"
+)
+
+// Delivery statuses.
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusDelivered  = "delivered"
+	StatusDead       = "dead"
+)
+
+// maxDeliveryAttempts is how many delivery attempts, the first one
+// included, are made before a delivery is marked dead.
+const maxDeliveryAttempts = 8
+
+type Webhook struct {
+	ID         int       `json:"id" db:"id"`
+	UserID     int       `json:"user_id" db:"user_id"`
+	URL        string    `json:"url" db:"url"`
+	Secret     string    `json:"-" db:"secret"`
+	EventTypes []string  `json:"event_types" db:"event_types"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type Delivery struct {
+	ID            int64     `json:"id" db:"id"`
+	WebhookID     int       `json:"webhook_id" db:"webhook_id"`
+	Event         string    `json:"event" db:"event"`
+	Attempts      int       `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at" db:"next_attempt_at"`
+	Status        string    `json:"status" db:"status"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// ThresholdRule is a user-defined bound checked against every ingested
+// sample for DeviceID/Metric by EvaluateThresholds.
+type ThresholdRule struct {
+	ID        int       `json:"id" db:"id"`
+	DeviceID  int       `json:"device_id" db:"device_id"`
+	Metric    string    `json:"metric" db:"metric"`
+	MinValue  *float64  `json:"min_value" db:"min_value"`
+	MaxValue  *float64  `json:"max_value" db:"max_value"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,84 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// offlineAfter is how long a device can go without telemetry before it is
+// considered offline.
+const offlineAfter = 10 * time.Minute
+
+// CheckOfflineDevices emits device.offline for every device whose last_seen
+// is older than offlineAfter and hasn't already been flagged since its most
+// recent telemetry, so the same silence period isn't reported twice.
+func CheckOfflineDevices(ctx context.Context, pool *pgxpool.Pool) error {
+	rows, err := pool.Query(ctx, `
+		SELECT id, name, last_seen FROM devices
+		WHERE last_seen IS NOT NULL
+		  AND last_seen < NOW() - $1::interval
+		  AND (offline_notified_at IS NULL OR offline_notified_at < last_seen)`,
+		fmt.Sprintf("%d seconds", int(offlineAfter.Seconds())),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to look up offline devices: %w", err)
+	}
+
+	type device struct {
+		id       int
+		name     string
+		lastSeen time.Time
+	}
+
+	var offline []device
+	for rows.Next() {
+		var d device
+		if err := rows.Scan(&d.id, &d.name, &d.lastSeen); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan offline device: %w", err)
+		}
+		offline = append(offline, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, d := range offline {
+		payload := map[string]any{
+			"device_id": d.id,
+			"name":      d.name,
+			"last_seen": d.lastSeen,
+		}
+		if err := Emit(ctx, pool, EventDeviceOffline, payload); err != nil {
+			return fmt.Errorf("failed to emit device.offline event: %w", err)
+		}
+		if _, err := pool.Exec(ctx, `UPDATE devices SET offline_notified_at = NOW() WHERE id = $1`, d.id); err != nil {
+			return fmt.Errorf("failed to record offline notification: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RunOfflineDetector periodically calls CheckOfflineDevices until ctx is
+// canceled.
+func RunOfflineDetector(ctx context.Context, pool *pgxpool.Pool) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := CheckOfflineDevices(ctx, pool); err != nil {
+				log.Printf("webhooks: offline device check failed: %v", err)
+			}
+		}
+	}
+}
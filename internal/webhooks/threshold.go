@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ThresholdSample is the minimal telemetry shape the threshold evaluator
+// needs; it is a separate type so this package doesn't depend on devices.
+type ThresholdSample struct {
+	DeviceID int
+	Metric   string
+	Value    float64
+}
+
+// EvaluateThresholds checks sample against any threshold_rules registered
+// for its device and metric, emitting telemetry.threshold_breached for
+// each bound it crosses. It is invoked from the telemetry ingest path.
+func EvaluateThresholds(ctx context.Context, pool *pgxpool.Pool, sample ThresholdSample) error {
+	rows, err := pool.Query(ctx,
+		`SELECT id, min_value, max_value FROM threshold_rules WHERE device_id = $1 AND metric = $2`,
+		sample.DeviceID, sample.Metric,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to look up threshold rules: %w", err)
+	}
+
+	type rule struct {
+		id       int
+		minValue *float64
+		maxValue *float64
+	}
+
+	var rules []rule
+	for rows.Next() {
+		var r rule
+		if err := rows.Scan(&r.id, &r.minValue, &r.maxValue); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan threshold rule: %w", err)
+		}
+		rules = append(rules, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range rules {
+		breached := (r.minValue != nil && sample.Value < *r.minValue) ||
+			(r.maxValue != nil && sample.Value > *r.maxValue)
+		if !breached {
+			continue
+		}
+
+		payload := map[string]any{
+			"device_id": sample.DeviceID,
+			"metric":    sample.Metric,
+			"value":     sample.Value,
+			"rule_id":   r.id,
+			"min_value": r.minValue,
+			"max_value": r.maxValue,
+		}
+		if err := Emit(ctx, pool, EventTelemetryThresholdBreach, payload); err != nil {
+			return fmt.Errorf("failed to emit threshold breach event: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Register a threshold rule for a device/metric pair
+func (h *Handlers) CreateThresholdRule(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	deviceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	var req struct {
+		Metric   string   `json:"metric" binding:"required"`
+		MinValue *float64 `json:"min_value"`
+		MaxValue *float64 `json:"max_value"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if req.MinValue == nil && req.MaxValue == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "At least one of min_value or max_value is required",
+		})
+		return
+	}
+
+	var rule ThresholdRule
+	query := `INSERT INTO threshold_rules (device_id, metric, min_value, max_value, created_at)
+			  VALUES ($1, $2, $3, $4, NOW())
+			  RETURNING id, device_id, metric, min_value, max_value, created_at`
+
+	err = h.DB.QueryRow(ctx, query, deviceID, req.Metric, req.MinValue, req.MaxValue).Scan(
+		&rule.ID, &rule.DeviceID, &rule.Metric, &rule.MinValue, &rule.MaxValue, &rule.CreatedAt,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create threshold rule",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":        "Threshold rule created successfully",
+		"threshold_rule": rule,
+	})
+}
+
+// Get all threshold rules registered for a device
+func (h *Handlers) GetThresholdRules(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	deviceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	query := `SELECT id, device_id, metric, min_value, max_value, created_at
+			  FROM threshold_rules WHERE device_id = $1 ORDER BY id`
+
+	rows, err := h.DB.Query(ctx, query, deviceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch threshold rules",
+		})
+		return
+	}
+	defer rows.Close()
+
+	var rules []ThresholdRule
+	for rows.Next() {
+		var rule ThresholdRule
+		if err := rows.Scan(&rule.ID, &rule.DeviceID, &rule.Metric, &rule.MinValue, &rule.MaxValue, &rule.CreatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to scan threshold rule data",
+			})
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error iterating over rows",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"threshold_rules": rules,
+	})
+}
+
+// Delete a threshold rule registered for a device
+func (h *Handlers) DeleteThresholdRule(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	deviceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid device ID",
+		})
+		return
+	}
+
+	ruleID, err := strconv.Atoi(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid threshold rule ID",
+		})
+		return
+	}
+
+	result, err := h.DB.Exec(ctx, "DELETE FROM threshold_rules WHERE id = $1 AND device_id = $2", ruleID, deviceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to delete threshold rule",
+		})
+		return
+	}
+
+	if result.RowsAffected() == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Threshold rule not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Threshold rule deleted successfully",
+	})
+}